@@ -0,0 +1,33 @@
+package cermanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/YLonely/cer-manager/pkg/reqid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// requestIDInterceptor stamps every unary call with a correlation ID and
+// logs its outcome, so an operator can trace one Get/Put round-trip
+// across the namespace and checkpoint services by request_id alone.
+func requestIDInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := reqid.New()
+		ctx = reqid.WithID(ctx, id)
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l := logger.With(
+			zap.String("request_id", id),
+			zap.String("method", info.FullMethod),
+			zap.Duration("latency", time.Since(start)),
+		)
+		if err != nil {
+			l.Error("rpc failed", zap.Error(err))
+		} else {
+			l.Info("rpc completed")
+		}
+		return resp, err
+	}
+}
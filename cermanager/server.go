@@ -2,8 +2,9 @@ package cermanager
 
 import (
 	"context"
-	"io"
+	"fmt"
 	"net"
+	"net/http/pprof"
 	"os"
 	"path"
 	"sync"
@@ -11,12 +12,16 @@ import (
 	cerm "github.com/YLonely/cer-manager"
 	"github.com/YLonely/cer-manager/api/types"
 	"github.com/YLonely/cer-manager/http"
-	"github.com/YLonely/cer-manager/log"
+	"github.com/YLonely/cer-manager/pkg/api/proto"
 	"github.com/YLonely/cer-manager/services"
 	"github.com/YLonely/cer-manager/services/checkpoint"
 	"github.com/YLonely/cer-manager/services/namespace"
-	"github.com/YLonely/cer-manager/utils"
+	"github.com/YLonely/cer-manager/state"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 )
 
 const DefaultRootPath = "/var/lib/cermanager"
@@ -24,8 +29,10 @@ const DefaultSocketName = "daemon.socket"
 
 type Server struct {
 	services   map[cerm.ServiceType]services.Service
+	grpcServer *grpc.Server
 	httpServer *http.Server
 	listener   net.Listener
+	logger     *zap.Logger
 	group      sync.WaitGroup
 }
 
@@ -43,11 +50,25 @@ func NewServer(httpPort int) (*Server, error) {
 	if err != nil {
 		return nil, err
 	}
-	checkpointSvr, err := checkpoint.New(DefaultRootPath)
+	// Built at debug so each service's log_level, applied via
+	// zap.IncreaseLevel, can only raise the threshold from here - never
+	// lower it. A base built at NewProduction's default Info level would
+	// make "debug" a silent no-op for every service.
+	baseConfig := zap.NewProductionConfig()
+	baseConfig.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	logger, err := baseConfig.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create logger")
+	}
+	stateMgr, err := state.NewManager(DefaultRootPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create state manager")
+	}
+	checkpointSvr, err := checkpoint.New(DefaultRootPath, logger, stateMgr)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create checkpoint service")
 	}
-	namespaceSvr, err := namespace.New(DefaultRootPath, checkpointSvr.(types.Supplier))
+	namespaceSvr, err := namespace.New(DefaultRootPath, checkpointSvr.(types.Supplier), logger, stateMgr)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create namespace service")
 	}
@@ -57,14 +78,25 @@ func NewServer(httpPort int) (*Server, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create http server")
 		}
+		httpServer.Handle("/metrics", promhttp.Handler())
+		httpServer.HandleFunc("/debug/pprof/", pprof.Index)
+		httpServer.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		httpServer.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		httpServer.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		httpServer.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	}
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(requestIDInterceptor(logger)))
+	proto.RegisterNamespaceServiceServer(grpcServer, namespaceSvr.(proto.NamespaceServiceServer))
+	proto.RegisterCheckpointServiceServer(grpcServer, checkpoint.NewGRPCServer(checkpointSvr))
 	svr := &Server{
 		services: map[cerm.ServiceType]services.Service{
 			cerm.NamespaceService:  namespaceSvr,
 			cerm.CheckpointService: checkpointSvr,
 		},
+		grpcServer: grpcServer,
 		listener:   listener,
 		httpServer: httpServer,
+		logger:     logger,
 	}
 	for _, service := range svr.services {
 		if err = service.Init(); err != nil {
@@ -83,61 +115,32 @@ func (s *Server) Start(ctx context.Context) chan error {
 			errorC <- err
 		}()
 	}
+	s.group.Add(1)
 	go func() {
-		for {
-			conn, err := s.listener.Accept()
-			if err != nil {
-				errorC <- err
-				return
-			}
-			s.group.Add(1)
-			go s.serve(ctx, conn, errorC)
-			select {
-			case <-ctx.Done():
-				return
-			default:
-			}
+		defer s.group.Done()
+		if err := s.grpcServer.Serve(s.listener); err != nil {
+			errorC <- err
 		}
 	}()
+	go func() {
+		<-ctx.Done()
+		s.grpcServer.GracefulStop()
+	}()
 	return errorC
 }
 
-func (s *Server) serve(ctx context.Context, conn net.Conn, errorC chan error) {
-	defer s.group.Done()
-	for {
-		svrType, err := utils.ReceiveServiceType(conn)
-		if err != nil {
-			if err != io.EOF {
-				log.Raw().WithError(err).Error("invalid request")
-			}
-			conn.Close()
-			return
-		}
-		if svr, exists := s.services[svrType]; !exists {
-			conn.Close()
-			log.Raw().Errorf("invalid service type %v", svrType)
-		} else {
-			svr.Handle(ctx, conn)
-		}
-		select {
-		case <-ctx.Done():
-			return
-		default:
-		}
-	}
-}
-
 func (s *Server) Shutdown() {
 	s.group.Wait()
 	for t, ss := range s.services {
 		if err := ss.Stop(); err != nil {
 			svrName := cerm.Type2Services[t]
-			log.Raw().Errorf("%s service shutdown with error %v", svrName, err)
+			s.logger.Error("service shutdown with error", zap.String("service", fmt.Sprint(svrName)), zap.Error(err))
 		}
 	}
 	if s.httpServer != nil {
 		if err := s.httpServer.Shutdown(); err != nil {
-			log.Raw().WithError(err).Error("http server shutdown with error")
+			s.logger.Error("http server shutdown with error", zap.Error(err))
 		}
 	}
+	s.logger.Sync()
 }
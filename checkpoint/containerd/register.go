@@ -0,0 +1,20 @@
+package containerd
+
+import (
+	"encoding/json"
+
+	cp "github.com/YLonely/cer-manager/checkpoint"
+)
+
+// init registers the containerd provider with the checkpoint package's
+// registry, so checkpoint.service can build one without importing this
+// package by name.
+func init() {
+	cp.Register("containerd", func(raw json.RawMessage) (cp.Provider, error) {
+		var config Config
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return nil, err
+		}
+		return NewProvider(config)
+	})
+}
@@ -0,0 +1,41 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Factory builds a Provider from its provider-specific, still-raw json
+// config. Provider packages supply one via Register instead of service.go
+// switching on a hard-coded type name.
+type Factory func(config json.RawMessage) (Provider, error)
+
+// ProviderRegistry maps a provider type name to the Factory that builds it.
+type ProviderRegistry struct {
+	m         sync.RWMutex
+	factories map[string]Factory
+}
+
+var defaultRegistry = &ProviderRegistry{factories: map[string]Factory{}}
+
+// Register adds factory under name to the default registry. Provider
+// packages call this from an init() func, mirroring database/sql drivers,
+// so callers only need to import them for their side effect. It panics on
+// a duplicate name since that always indicates a programming error.
+func Register(name string, factory Factory) {
+	defaultRegistry.m.Lock()
+	defer defaultRegistry.m.Unlock()
+	if _, exists := defaultRegistry.factories[name]; exists {
+		panic(fmt.Sprintf("checkpoint: provider %q already registered", name))
+	}
+	defaultRegistry.factories[name] = factory
+}
+
+// Lookup returns the Factory registered under name, if any.
+func Lookup(name string) (Factory, bool) {
+	defaultRegistry.m.RLock()
+	defer defaultRegistry.m.RUnlock()
+	factory, exists := defaultRegistry.factories[name]
+	return factory, exists
+}
@@ -0,0 +1,57 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type fakeProvider struct{ name string }
+
+func (p *fakeProvider) Prepare(ref, target string) error { return nil }
+func (p *fakeProvider) Remove(target string) error       { return nil }
+
+func fakeFactory(name string) Factory {
+	return func(json.RawMessage) (Provider, error) {
+		return &fakeProvider{name: name}, nil
+	}
+}
+
+func TestRegisterAndLookup(t *testing.T) {
+	name := "fake-registry-test-a"
+	t.Cleanup(func() { delete(defaultRegistry.factories, name) })
+
+	if _, exists := Lookup(name); exists {
+		t.Fatalf("expected %q to be unregistered", name)
+	}
+	Register(name, fakeFactory(name))
+	factory, exists := Lookup(name)
+	if !exists {
+		t.Fatalf("expected %q to be registered", name)
+	}
+	p, err := factory(nil)
+	if err != nil {
+		t.Fatalf("factory: %v", err)
+	}
+	if p.(*fakeProvider).name != name {
+		t.Fatalf("unexpected provider %+v", p)
+	}
+}
+
+func TestLookupMiss(t *testing.T) {
+	if _, exists := Lookup("no-such-provider"); exists {
+		t.Fatal("expected Lookup to report a miss for an unregistered name")
+	}
+}
+
+func TestRegisterDuplicatePanics(t *testing.T) {
+	name := "fake-registry-test-b"
+	t.Cleanup(func() { delete(defaultRegistry.factories, name) })
+
+	Register(name, fakeFactory(name))
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected duplicate Register to panic")
+		}
+	}()
+	Register(name, fakeFactory(name))
+}
@@ -1,38 +1,287 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	stderrors "errors"
+	"io"
 	"net"
-)
+	"sync"
+	"syscall"
 
-const (
-	defaultSocketPath = "/var/lib/cermanager/daemon.socket"
+	ns "github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/cer-manager/pkg/api/proto"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func New(config Config) (*Client, error) {
-	var c net.Conn
-	var err error
-	if c, err = net.Dial("unix", config.SocketPath); err != nil {
+const defaultSocketPath = "/var/lib/cermanager/daemon.socket"
+
+// Dialer opens the transport connection to the daemon. Tests substitute one
+// backed by net.Pipe via WithDialer instead of dialing a real unix socket.
+type Dialer func(ctx context.Context, addr string) (net.Conn, error)
+
+func unixDialer(ctx context.Context, addr string) (net.Conn, error) {
+	var d net.Dialer
+	return d.DialContext(ctx, "unix", addr)
+}
+
+// Config configures a Client or Pool.
+type Config struct {
+	SocketPath string
+	Dialer     Dialer
+}
+
+// Option customizes a Config.
+type Option func(*Config)
+
+// WithDialer overrides how a Client or Pool opens its transport connection.
+func WithDialer(d Dialer) Option {
+	return func(c *Config) {
+		c.Dialer = d
+	}
+}
+
+func dial(config Config) (*grpc.ClientConn, error) {
+	dialer := config.Dialer
+	if dialer == nil {
+		dialer = unixDialer
+	}
+	return grpc.Dial(
+		config.SocketPath,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(dialer),
+	)
+}
+
+// Client is a typed handle on the daemon's NamespaceService and
+// CheckpointService RPCs. It transparently redials once if a call fails
+// because the underlying connection died, e.g. the daemon restarted and
+// left our end of the unix socket broken. A Client is shared across
+// goroutines by Pool, so cc is guarded by ccMu rather than swapped bare.
+type Client struct {
+	config Config
+	ccMu   sync.RWMutex
+	cc     *grpc.ClientConn
+}
+
+func New(config Config, opts ...Option) (*Client, error) {
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if config.SocketPath == "" {
+		config.SocketPath = defaultSocketPath
+	}
+	cc, err := dial(config)
+	if err != nil {
 		return nil, err
 	}
-	return &Client{
-		c: c,
-	}, nil
+	return &Client{config: config, cc: cc}, nil
 }
 
 func Default() (*Client, error) {
-	return New(Config{
-		SocketPath: defaultSocketPath,
+	return New(Config{SocketPath: defaultSocketPath})
+}
+
+func (client *Client) Close() error {
+	client.ccMu.RLock()
+	defer client.ccMu.RUnlock()
+	return client.cc.Close()
+}
+
+// conn returns the client's current connection.
+func (client *Client) conn() *grpc.ClientConn {
+	client.ccMu.RLock()
+	defer client.ccMu.RUnlock()
+	return client.cc
+}
+
+// GetNamespaceResponse is the typed result of GetNamespace. Info is decoded
+// from the wire's gob-encoded bytes, so callers get back whatever value the
+// namespace manager produced instead of a raw blob they'd have to decode
+// themselves.
+//
+// Info (and GetNamespace's arg) stay interface{} rather than a real
+// per-namespace-type message: each ns.NamespaceType's manager defines its
+// own argument/info shape, and modeling that in the .proto would mean a
+// oneof keyed on ns.NamespaceType mirroring those manager-side types one
+// for one. That's deliberately left out of this request - the manager
+// types aren't stable/exported enough yet to pin the wire schema to them -
+// so the bytes field is still a gob-encoded blob, just carried over gRPC
+// instead of the old hand-rolled framing.
+type GetNamespaceResponse struct {
+	NsId int32
+	Pid  int32
+	Fd   int32
+	Info interface{}
+}
+
+// GetNamespace calls NamespaceService.Get. arg is the manager's native
+// argument for t, gob-encoded on the wire the same way the daemon expects it.
+func (client *Client) GetNamespace(ctx context.Context, t ns.NamespaceType, arg interface{}) (*GetNamespaceResponse, error) {
+	var argBuf bytes.Buffer
+	if arg != nil {
+		if err := gob.NewEncoder(&argBuf).Encode(&arg); err != nil {
+			return nil, errors.Wrap(err, "failed to encode arg")
+		}
+	}
+	var rsp *proto.GetNamespaceResponse
+	err := client.call(ctx, func(cc *grpc.ClientConn) error {
+		var err error
+		rsp, err = proto.NewNamespaceServiceClient(cc).Get(ctx, &proto.GetNamespaceRequest{Type: int32(t), Arg: argBuf.Bytes()})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var info interface{}
+	if len(rsp.Info) > 0 {
+		if err := gob.NewDecoder(bytes.NewReader(rsp.Info)).Decode(&info); err != nil {
+			return nil, errors.Wrap(err, "failed to decode info")
+		}
+	}
+	return &GetNamespaceResponse{NsId: rsp.NsId, Pid: rsp.Pid, Fd: rsp.Fd, Info: info}, nil
+}
+
+// PutNamespace calls NamespaceService.Put, surfacing the response's Error
+// field as a Go error since the RPC itself can't fail the namespace release.
+func (client *Client) PutNamespace(ctx context.Context, t, id int32) error {
+	return client.call(ctx, func(cc *grpc.ClientConn) error {
+		rsp, err := proto.NewNamespaceServiceClient(cc).Put(ctx, &proto.PutNamespaceRequest{Type: t, Id: id})
+		if err != nil {
+			return err
+		}
+		if rsp.Error != "" {
+			return errors.New(rsp.Error)
+		}
+		return nil
 	})
 }
 
-type Config struct {
-	SocketPath string
+// GetCheckpoint calls CheckpointService.Get, returning the prepared target's
+// path on disk.
+func (client *Client) GetCheckpoint(ctx context.Context, ref string) (string, error) {
+	var rsp *proto.GetCheckpointResponse
+	err := client.call(ctx, func(cc *grpc.ClientConn) error {
+		var err error
+		rsp, err = proto.NewCheckpointServiceClient(cc).Get(ctx, &proto.GetCheckpointRequest{Ref: ref})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return rsp.Path, nil
 }
 
-type Client struct {
-	c net.Conn
+// ListNamespaces calls NamespaceService.List, returning the current pool
+// state of every namespace type.
+func (client *Client) ListNamespaces(ctx context.Context) ([]*proto.NamespacePoolInfo, error) {
+	var rsp *proto.ListNamespacesResponse
+	err := client.call(ctx, func(cc *grpc.ClientConn) error {
+		var err error
+		rsp, err = proto.NewNamespaceServiceClient(cc).List(ctx, &proto.ListNamespacesRequest{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Pools, nil
 }
 
-func (client *Client) Close() error {
-	return client.c.Close()
+// DropNamespace calls NamespaceService.Drop, forcing a namespace back into
+// its pool.
+func (client *Client) DropNamespace(ctx context.Context, t, id int32) error {
+	return client.call(ctx, func(cc *grpc.ClientConn) error {
+		rsp, err := proto.NewNamespaceServiceClient(cc).Drop(ctx, &proto.DropNamespaceRequest{Type: t, Id: id})
+		if err != nil {
+			return err
+		}
+		if rsp.Error != "" {
+			return errors.New(rsp.Error)
+		}
+		return nil
+	})
+}
+
+// ListCheckpoints calls CheckpointService.List, returning every prepared
+// target and its reference count.
+func (client *Client) ListCheckpoints(ctx context.Context) ([]*proto.CheckpointTargetInfo, error) {
+	var rsp *proto.ListCheckpointsResponse
+	err := client.call(ctx, func(cc *grpc.ClientConn) error {
+		var err error
+		rsp, err = proto.NewCheckpointServiceClient(cc).List(ctx, &proto.ListCheckpointsRequest{})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Targets, nil
+}
+
+// PrepareCheckpoint calls CheckpointService.Prepare, eagerly preparing ref
+// without taking a reference on it.
+func (client *Client) PrepareCheckpoint(ctx context.Context, ref string) (string, error) {
+	var rsp *proto.PrepareCheckpointResponse
+	err := client.call(ctx, func(cc *grpc.ClientConn) error {
+		var err error
+		rsp, err = proto.NewCheckpointServiceClient(cc).Prepare(ctx, &proto.PrepareCheckpointRequest{Ref: ref})
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return rsp.Path, nil
+}
+
+// EvictCheckpoint calls CheckpointService.Evict, removing a prepared target
+// from the cache.
+func (client *Client) EvictCheckpoint(ctx context.Context, ref string) error {
+	return client.call(ctx, func(cc *grpc.ClientConn) error {
+		rsp, err := proto.NewCheckpointServiceClient(cc).Evict(ctx, &proto.EvictCheckpointRequest{Ref: ref})
+		if err != nil {
+			return err
+		}
+		if rsp.Error != "" {
+			return errors.New(rsp.Error)
+		}
+		return nil
+	})
+}
+
+// call invokes fn against the client's current connection, redialing and
+// retrying once if the failure looks like a dead connection rather than a
+// real RPC error. Pool hands the same *Client to concurrent goroutines, so
+// the old connection is only closed once the new one is safely swapped in,
+// and the swap itself is guarded by ccMu to avoid a data race on cc.
+func (client *Client) call(ctx context.Context, fn func(cc *grpc.ClientConn) error) error {
+	old := client.conn()
+	err := fn(old)
+	if !shouldReconnect(err) {
+		return err
+	}
+	cc, dialErr := dial(client.config)
+	if dialErr != nil {
+		return err
+	}
+	client.ccMu.Lock()
+	client.cc = cc
+	client.ccMu.Unlock()
+	old.Close()
+	return fn(cc)
+}
+
+func shouldReconnect(err error) bool {
+	if err == nil {
+		return false
+	}
+	if stderrors.Is(err, io.ErrClosedPipe) || stderrors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	if st, ok := status.FromError(err); ok {
+		return st.Code() == codes.Unavailable
+	}
+	return false
 }
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	ns "github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/cer-manager/pkg/api/proto"
+	"google.golang.org/grpc"
+)
+
+// pipeListener hands out net.Pipe server ends to a grpc.Server, so tests can
+// drive the real client/server RPC path without a unix socket.
+type pipeListener struct {
+	conns chan net.Conn
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn)}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	c, ok := <-l.conns
+	if !ok {
+		return nil, io.EOF
+	}
+	return c, nil
+}
+
+func (l *pipeListener) Close() error   { close(l.conns); return nil }
+func (l *pipeListener) Addr() net.Addr { return pipeAddr{} }
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+// dialer returns a Dialer that hands the client end of a fresh net.Pipe to
+// grpc and feeds the server end to lis, mimicking WithDialer's net.Pipe use
+// in production tests.
+func (l *pipeListener) dialer() Dialer {
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		client, server := net.Pipe()
+		l.conns <- server
+		return client, nil
+	}
+}
+
+type fakeNamespaceServer struct {
+	proto.UnimplementedNamespaceServiceServer
+}
+
+func (f *fakeNamespaceServer) Get(ctx context.Context, r *proto.GetNamespaceRequest) (*proto.GetNamespaceResponse, error) {
+	return &proto.GetNamespaceResponse{NsId: 1, Pid: 2, Fd: 3, Info: r.Arg}, nil
+}
+
+type fakeCheckpointServer struct {
+	proto.UnimplementedCheckpointServiceServer
+}
+
+func (f *fakeCheckpointServer) Get(ctx context.Context, r *proto.GetCheckpointRequest) (*proto.GetCheckpointResponse, error) {
+	return &proto.GetCheckpointResponse{Path: "/checkpoints/" + r.Ref}, nil
+}
+
+func newTestServer(t *testing.T) (*Client, func()) {
+	t.Helper()
+	lis := newPipeListener()
+	srv := grpc.NewServer()
+	proto.RegisterNamespaceServiceServer(srv, &fakeNamespaceServer{})
+	proto.RegisterCheckpointServiceServer(srv, &fakeCheckpointServer{})
+	go srv.Serve(lis)
+
+	cl, err := New(Config{SocketPath: "pipe"}, WithDialer(lis.dialer()))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return cl, func() {
+		cl.Close()
+		srv.Stop()
+	}
+}
+
+func TestClientGetNamespaceRoundTripsTypedArgAndInfo(t *testing.T) {
+	cl, cleanup := newTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	rsp, err := cl.GetNamespace(ctx, ns.NamespaceType(0), "hello")
+	if err != nil {
+		t.Fatalf("GetNamespace: %v", err)
+	}
+	if rsp.NsId != 1 || rsp.Pid != 2 || rsp.Fd != 3 {
+		t.Fatalf("unexpected response: %+v", rsp)
+	}
+	if rsp.Info != "hello" {
+		t.Fatalf("expected Info to decode back to %q, got %v", "hello", rsp.Info)
+	}
+}
+
+func TestClientGetCheckpointReturnsPlainPath(t *testing.T) {
+	cl, cleanup := newTestServer(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	path, err := cl.GetCheckpoint(ctx, "myref")
+	if err != nil {
+		t.Fatalf("GetCheckpoint: %v", err)
+	}
+	if path != "/checkpoints/myref" {
+		t.Fatalf("unexpected path %q", path)
+	}
+}
+
+func TestShouldReconnect(t *testing.T) {
+	if shouldReconnect(nil) {
+		t.Fatal("nil error should not trigger reconnect")
+	}
+	if !shouldReconnect(io.ErrClosedPipe) {
+		t.Fatal("a closed-pipe error should trigger reconnect")
+	}
+}
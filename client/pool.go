@@ -0,0 +1,60 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Pool multiplexes calls over a small set of connections to the daemon, so
+// callers that issue many concurrent requests don't each need their own
+// dial and don't serialize behind a single *grpc.ClientConn's stream limit.
+type Pool struct {
+	config Config
+	m      sync.Mutex
+	conns  []*Client
+	next   int
+}
+
+// NewPool dials size connections to the daemon upfront. size is clamped to
+// at least 1.
+func NewPool(config Config, size int, opts ...Option) (*Pool, error) {
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if size < 1 {
+		size = 1
+	}
+	p := &Pool{config: config}
+	for i := 0; i < size; i++ {
+		c, err := New(config)
+		if err != nil {
+			p.Close()
+			return nil, errors.Wrap(err, "failed to dial pool connection")
+		}
+		p.conns = append(p.conns, c)
+	}
+	return p, nil
+}
+
+// Get returns the next connection in round-robin order.
+func (p *Pool) Get() *Client {
+	p.m.Lock()
+	defer p.m.Unlock()
+	c := p.conns[p.next%len(p.conns)]
+	p.next++
+	return c
+}
+
+// Close closes every connection in the pool, returning the first error.
+func (p *Pool) Close() error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	var firstErr error
+	for _, c := range p.conns {
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
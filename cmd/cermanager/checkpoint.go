@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/YLonely/cer-manager/client"
+	"github.com/urfave/cli"
+)
+
+var checkpointCommand = cli.Command{
+	Name:  "checkpoint",
+	Usage: "inspect and manage prepared checkpoint targets",
+	Subcommands: []cli.Command{
+		listCheckpointsCommand,
+		prepareCheckpointCommand,
+		evictCheckpointCommand,
+	},
+}
+
+var listCheckpointsCommand = cli.Command{
+	Name:  "list",
+	Usage: "list prepared checkpoint targets and their reference counts",
+	Action: func(c *cli.Context) error {
+		cl, err := client.Default()
+		if err != nil {
+			return err
+		}
+		defer cl.Close()
+		targets, err := cl.ListCheckpoints(context.Background())
+		if err != nil {
+			return err
+		}
+		for _, t := range targets {
+			fmt.Printf("path=%s ref_count=%d\n", t.Path, t.RefCount)
+		}
+		return nil
+	},
+}
+
+var prepareCheckpointCommand = cli.Command{
+	Name:      "prepare",
+	Usage:     "eagerly prepare a checkpoint reference",
+	ArgsUsage: "<ref>",
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 1 {
+			return cli.NewExitError("usage: checkpoint prepare <ref>", 1)
+		}
+		cl, err := client.Default()
+		if err != nil {
+			return err
+		}
+		defer cl.Close()
+		path, err := cl.PrepareCheckpoint(context.Background(), c.Args().Get(0))
+		if err != nil {
+			return err
+		}
+		fmt.Println(path)
+		return nil
+	},
+}
+
+var evictCheckpointCommand = cli.Command{
+	Name:      "evict",
+	Usage:     "evict a prepared checkpoint target",
+	ArgsUsage: "<ref>",
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 1 {
+			return cli.NewExitError("usage: checkpoint evict <ref>", 1)
+		}
+		cl, err := client.Default()
+		if err != nil {
+			return err
+		}
+		defer cl.Close()
+		return cl.EvictCheckpoint(context.Background(), c.Args().Get(0))
+	},
+}
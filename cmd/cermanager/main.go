@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli"
+)
+
+func main() {
+	app := cli.NewApp()
+	app.Name = "cermanager"
+	app.Usage = "manage the cer-manager daemon and its pooled resources"
+	app.Commands = []cli.Command{
+		startCommand,
+		namespaceCommand,
+		checkpointCommand,
+	}
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/YLonely/cer-manager/client"
+	"github.com/urfave/cli"
+)
+
+var namespaceCommand = cli.Command{
+	Name:  "namespace",
+	Usage: "inspect and manage pooled namespaces",
+	Subcommands: []cli.Command{
+		listNamespacesCommand,
+		dropNamespaceCommand,
+	},
+}
+
+var listNamespacesCommand = cli.Command{
+	Name:  "list",
+	Usage: "list the pool state of every namespace type",
+	Action: func(c *cli.Context) error {
+		cl, err := client.Default()
+		if err != nil {
+			return err
+		}
+		defer cl.Close()
+		pools, err := cl.ListNamespaces(context.Background())
+		if err != nil {
+			return err
+		}
+		for _, p := range pools {
+			fmt.Printf("type=%d capacity=%d in_use=%d\n", p.Type, p.Capacity, p.InUse)
+		}
+		return nil
+	},
+}
+
+var dropNamespaceCommand = cli.Command{
+	Name:      "drop",
+	Usage:     "force a namespace back into its pool",
+	ArgsUsage: "<type> <id>",
+	Action: func(c *cli.Context) error {
+		if c.NArg() != 2 {
+			return cli.NewExitError("usage: namespace drop <type> <id>", 1)
+		}
+		t, err := strconv.Atoi(c.Args().Get(0))
+		if err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		id, err := strconv.Atoi(c.Args().Get(1))
+		if err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		cl, err := client.Default()
+		if err != nil {
+			return err
+		}
+		defer cl.Close()
+		return cl.DropNamespace(context.Background(), int32(t), int32(id))
+	},
+}
@@ -6,6 +6,8 @@ import (
 	"os/signal"
 
 	"github.com/YLonely/cer-manager/cermanager"
+	_ "github.com/YLonely/cer-manager/checkpoint/ccfs"
+	_ "github.com/YLonely/cer-manager/checkpoint/containerd"
 	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/services"
 	"github.com/YLonely/cer-manager/signals"
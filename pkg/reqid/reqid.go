@@ -0,0 +1,27 @@
+// Package reqid attaches a correlation ID to a context so a single
+// Get/Put round-trip can be traced across services in the logs.
+package reqid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type contextKey struct{}
+
+// New generates a fresh correlation ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// WithID returns a copy of ctx carrying id, retrievable via FromContext.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the correlation ID stored in ctx, or "" if none was set.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,37 @@
+package checkpoint
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	targetsPrepared = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cermanager",
+		Subsystem: "checkpoint",
+		Name:      "targets_prepared",
+		Help:      "Number of checkpoint targets currently prepared on disk.",
+	})
+
+	prepareDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "cermanager",
+		Subsystem: "checkpoint",
+		Name:      "prepare_duration_seconds",
+		Help:      "Latency of provider.Prepare calls.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	getTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cermanager",
+		Subsystem: "checkpoint",
+		Name:      "get_total",
+		Help:      "Total Get calls by cache result.",
+	}, []string{"result"})
+
+	activeRefs = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "cermanager",
+		Subsystem: "checkpoint",
+		Name:      "active_refs",
+		Help:      "Number of checkpoint references handed out by the reference manager.",
+	})
+)
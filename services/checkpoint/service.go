@@ -9,22 +9,24 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
-	api "github.com/YLonely/cer-manager/api/services/checkpoint"
 	cp "github.com/YLonely/cer-manager/checkpoint"
-	"github.com/YLonely/cer-manager/checkpoint/ccfs"
-	"github.com/YLonely/cer-manager/checkpoint/containerd"
-	"github.com/YLonely/cer-manager/utils"
+	"github.com/YLonely/cer-manager/pkg/api/proto"
+	"github.com/YLonely/cer-manager/pkg/reqid"
+	"github.com/YLonely/cer-manager/state"
 
 	"path"
 
-	cerm "github.com/YLonely/cer-manager"
-	"github.com/YLonely/cer-manager/log"
 	"github.com/YLonely/cer-manager/services"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-func New(root string) (services.Service, error) {
+func New(root string, baseLogger *zap.Logger, stateMgr *state.Manager) (services.Service, error) {
 	const configName = "checkpoint_service.json"
 	content, err := ioutil.ReadFile(path.Join(root, configName))
 	if err != nil {
@@ -32,15 +34,28 @@ func New(root string) (services.Service, error) {
 	}
 	var providerConfigObj json.RawMessage
 	c := config{
-		Config: &providerConfigObj,
+		Config:   &providerConfigObj,
+		LogLevel: "info",
 	}
 	if json.Unmarshal(content, &c); err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal config file")
 	}
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return nil, errors.Wrap(err, "invalid log_level")
+	}
+	logger := baseLogger.WithOptions(zap.IncreaseLevel(lvl))
+	if c.LogSampling != nil {
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Second, c.LogSampling.Initial, c.LogSampling.Thereafter)
+		}))
+	}
+	logger = logger.With(zap.String("service", "checkpoint"))
 	s := &service{
-		root:    path.Join(root, "checkpoint"),
-		router:  services.NewRouter(),
-		targets: map[string]struct{}{},
+		root:     path.Join(root, "checkpoint"),
+		targets:  map[string]struct{}{},
+		logger:   logger,
+		stateMgr: stateMgr,
 	}
 	err = s.initProvider(c)
 	if err != nil {
@@ -51,12 +66,18 @@ func New(root string) (services.Service, error) {
 
 type service struct {
 	root         string
-	router       services.Router
 	provider     cp.Provider
 	referenceMgr cp.ReferenceManager
 	//targets records all the target path where the checkpoint files located
-	targets map[string]struct{}
-	m       sync.Mutex
+	targets  map[string]struct{}
+	m        sync.Mutex
+	logger   *zap.Logger
+	stateMgr *state.Manager
+}
+
+// targetRecord is the state.Manager payload for a prepared checkpoint target.
+type targetRecord struct {
+	Ref string `json:"ref"`
 }
 
 type config struct {
@@ -64,24 +85,63 @@ type config struct {
 	Type string `json:"type"`
 	// config for the checkpoint provider
 	Config interface{} `json:"config"`
+	// LogLevel is a zapcore.Level string applied on top of the daemon's base logger.
+	LogLevel string `json:"log_level"`
+	// LogSampling configures zap's log sampler on top of the daemon's base
+	// logger. A nil value leaves sampling disabled.
+	LogSampling *LogSampling `json:"log_sampling,omitempty"`
+}
+
+// LogSampling mirrors the knobs of zapcore.NewSamplerWithOptions: within
+// each one-second tick, the first Initial entries of a given level+message
+// are logged, and only every Thereafter-th one after that.
+type LogSampling struct {
+	Initial    int `json:"initial"`
+	Thereafter int `json:"thereafter"`
 }
 
 var _ services.Service = &service{}
 
+// grpcServer adapts service to proto.CheckpointServiceServer. It is kept
+// separate from service because service.Get(ref string) already exists
+// as the types.Supplier method consumed by the namespace service's mount
+// manager, and its signature doesn't match the RPC's Get(ctx, *proto.GetCheckpointRequest).
+type grpcServer struct {
+	*service
+}
+
+var _ proto.CheckpointServiceServer = &grpcServer{}
+
+// NewGRPCServer wraps svc for registration on a grpc.Server.
+func NewGRPCServer(svc services.Service) proto.CheckpointServiceServer {
+	return &grpcServer{svc.(*service)}
+}
+
 func (s *service) Init() error {
 	if err := os.MkdirAll(s.root, 0755); err != nil {
 		return err
 	}
-	s.router.AddHandler(api.MethodGetCheckpoint, s.handleGetCheckpoint)
-	log.Logger(cerm.CheckpointService, "Init").Info("Service initialized")
+	// Anything still recorded here belongs to a previous, uncleanly
+	// stopped process: s.targets always starts empty, so every entry
+	// found is an orphan target left on disk.
+	for target := range s.stateMgr.Entries(state.KindCheckpoint) {
+		if err := s.provider.Remove(target); err != nil {
+			s.logger.Warn("failed to remove orphan checkpoint target", zap.String("target", target), zap.Error(err))
+			continue
+		}
+		if err := s.stateMgr.Remove(state.KindCheckpoint, target); err != nil {
+			s.logger.Warn("failed to clear orphan checkpoint state entry", zap.String("target", target), zap.Error(err))
+		}
+	}
+	s.logger.Info("service initialized")
 	return nil
 }
 
+// Handle is kept to satisfy services.Service but is no longer invoked:
+// the CheckpointService RPCs are now served directly by the grpc.Server
+// registered in cermanager.NewServer.
 func (s *service) Handle(ctx context.Context, c net.Conn) {
-	if err := s.router.Handle(c); err != nil {
-		log.Logger(cerm.CheckpointService, "").Error(err.Error())
-		c.Close()
-	}
+	c.Close()
 }
 
 func (s *service) Stop() error {
@@ -89,7 +149,14 @@ func (s *service) Stop() error {
 	for t := range s.targets {
 		if err := s.provider.Remove(t); err != nil {
 			failed = append(failed, fmt.Sprintf("remove %s with error %s", t, err.Error()))
+			continue
 		}
+		if err := s.stateMgr.Remove(state.KindCheckpoint, t); err != nil {
+			failed = append(failed, fmt.Sprintf("clear state entry for %s with error %s", t, err.Error()))
+		}
+	}
+	if s.referenceMgr != nil {
+		activeRefs.Set(0)
 	}
 	if len(failed) != 0 {
 		return errors.New(strings.Join(failed, ";"))
@@ -105,65 +172,148 @@ func (s *service) Get(ref string) (string, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 	if _, exists := s.targets[target]; exists {
+		getTotal.WithLabelValues("hit").Inc()
 		return target, nil
 	}
+	getTotal.WithLabelValues("miss").Inc()
 	if err := os.MkdirAll(target, 0755); err != nil {
 		return "", errors.Wrap(err, "failed to create dir "+target)
 	}
-	if err := s.provider.Prepare(ref, target); err != nil {
+	start := time.Now()
+	err := s.provider.Prepare(ref, target)
+	prepareDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
 		return "", err
 	}
+	if err := s.stateMgr.Put(state.KindCheckpoint, target, targetRecord{Ref: ref}); err != nil {
+		return "", errors.Wrap(err, "failed to persist checkpoint target state")
+	}
 	s.targets[target] = struct{}{}
+	targetsPrepared.Set(float64(len(s.targets)))
 	return target, nil
 }
 
-func (s *service) handleGetCheckpoint(c net.Conn) error {
-	var r api.GetCheckpointRequest
-	if err := utils.ReceiveObject(c, &r); err != nil {
-		return err
-	}
-	log.WithInterface(log.Logger(cerm.CheckpointService, "GetCheckpoint"), "request", r).Info()
-	var resp api.GetCheckpointResponse
-	var err error
-	resp.Path, err = s.Get(r.Ref)
+// GetCheckpoint backs grpcServer.Get; it's defined on service rather than
+// grpcServer so it can reach the unexported fields below directly.
+func (s *service) GetCheckpoint(ctx context.Context, r *proto.GetCheckpointRequest) (*proto.GetCheckpointResponse, error) {
+	l := s.logger.With(zap.String("request_id", reqid.FromContext(ctx)))
+	l.Info("GetCheckpoint", zap.Any("request", r))
+	rsp := &proto.GetCheckpointResponse{}
+	target, err := s.Get(r.Ref)
 	if err != nil {
-		log.Logger(cerm.CheckpointService, "GetCheckpoint").Error(err.Error())
+		l.Error("GetCheckpoint failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
 	}
+	rsp.Path = target
 	if s.referenceMgr != nil {
 		s.referenceMgr.Add(r.Ref)
+		s.m.Lock()
+		activeRefs.Set(float64(s.totalActiveRefs()))
+		s.m.Unlock()
 	}
-	if err := utils.SendObject(c, resp); err != nil {
-		return err
+	l.Info("GetCheckpoint", zap.Any("response", rsp))
+	return rsp, nil
+}
+
+// Get implements proto.CheckpointServiceServer.
+func (g *grpcServer) Get(ctx context.Context, r *proto.GetCheckpointRequest) (*proto.GetCheckpointResponse, error) {
+	return g.service.GetCheckpoint(ctx, r)
+}
+
+// refCounter is implemented by reference managers that can report how many
+// references a ref currently holds; List degrades to a zero count for ones
+// that can't.
+type refCounter interface {
+	Count(ref string) int
+}
+
+// List implements proto.CheckpointServiceServer, dumping every prepared
+// target and its reference count for operators inspecting the cache.
+func (s *service) List(ctx context.Context, r *proto.ListCheckpointsRequest) (*proto.ListCheckpointsResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+	rsp := &proto.ListCheckpointsResponse{}
+	for target := range s.targets {
+		info := &proto.CheckpointTargetInfo{Path: target}
+		if counter, ok := s.referenceMgr.(refCounter); ok {
+			ref := strings.TrimPrefix(target, s.root+string(os.PathSeparator))
+			info.RefCount = int32(counter.Count(ref))
+		}
+		rsp.Targets = append(rsp.Targets, info)
 	}
-	log.WithInterface(log.Logger(cerm.CheckpointService, "GetCheckpoint"), "response", resp).Info()
-	return nil
+	return rsp, nil
+}
+
+// Prepare implements proto.CheckpointServiceServer, eagerly preparing ref
+// without waiting for a consumer to Get it and without taking a reference.
+func (s *service) Prepare(ctx context.Context, r *proto.PrepareCheckpointRequest) (*proto.PrepareCheckpointResponse, error) {
+	l := s.logger.With(zap.String("request_id", reqid.FromContext(ctx)))
+	l.Info("Prepare", zap.Any("request", r))
+	target, err := s.Get(r.Ref)
+	if err != nil {
+		l.Error("Prepare failed", zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &proto.PrepareCheckpointResponse{Path: target}, nil
+}
+
+// Evict implements proto.CheckpointServiceServer, removing a prepared target
+// from the cache ahead of Stop.
+func (s *service) Evict(ctx context.Context, r *proto.EvictCheckpointRequest) (*proto.EvictCheckpointResponse, error) {
+	l := s.logger.With(zap.String("request_id", reqid.FromContext(ctx)))
+	l.Info("Evict", zap.Any("request", r))
+	target := path.Join(s.root, r.Ref)
+	s.m.Lock()
+	defer s.m.Unlock()
+	if _, exists := s.targets[target]; !exists {
+		return &proto.EvictCheckpointResponse{}, nil
+	}
+	if err := s.provider.Remove(target); err != nil {
+		return &proto.EvictCheckpointResponse{Error: err.Error()}, nil
+	}
+	delete(s.targets, target)
+	targetsPrepared.Set(float64(len(s.targets)))
+	if s.referenceMgr != nil {
+		activeRefs.Set(float64(s.totalActiveRefs()))
+	}
+	if err := s.stateMgr.Remove(state.KindCheckpoint, target); err != nil {
+		l.Warn("failed to clear checkpoint state", zap.Error(err))
+	}
+	return &proto.EvictCheckpointResponse{}, nil
+}
+
+// totalActiveRefs sums the reference manager's live count across every
+// prepared target, so the active_refs gauge reflects actual outstanding
+// references instead of a monotonic Add() counter. Must be called with
+// s.m held. Returns 0 if referenceMgr doesn't support counting.
+func (s *service) totalActiveRefs() int {
+	counter, ok := s.referenceMgr.(refCounter)
+	if !ok {
+		return 0
+	}
+	total := 0
+	for target := range s.targets {
+		ref := strings.TrimPrefix(target, s.root+string(os.PathSeparator))
+		total += counter.Count(ref)
+	}
+	return total
 }
 
 func (s *service) initProvider(c config) error {
-	var p cp.Provider
-	var err error
-	switch c.Type {
-	case "ccfs":
-		var cacheConfig ccfs.Config
-		if err = json.Unmarshal(*(c.Config.(*json.RawMessage)), &cacheConfig); err != nil {
-			return err
-		}
-		p, err = ccfs.NewProvider(cacheConfig)
-		if err != nil {
-			return errors.Wrap(err, "failed to create ccfs provider")
-		}
-		s.referenceMgr = p.(cp.ReferenceManager)
-	case "containerd":
-		var cacheConfig containerd.Config
-		if err = json.Unmarshal(*(c.Config.(*json.RawMessage)), &cacheConfig); err != nil {
-			return err
-		}
-		p, err = containerd.NewProvider(cacheConfig)
-		if err != nil {
-			return errors.Wrap(err, "failed to create containerd provider")
-		}
-	default:
-		return errors.New("invalid provider type")
+	factory, exists := cp.Lookup(c.Type)
+	if !exists {
+		return errors.Errorf("invalid provider type %q", c.Type)
+	}
+	raw, ok := c.Config.(*json.RawMessage)
+	if !ok || raw == nil {
+		return errors.New("missing provider config")
+	}
+	p, err := factory(*raw)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create %s provider", c.Type)
+	}
+	if refMgr, ok := p.(cp.ReferenceManager); ok {
+		s.referenceMgr = refMgr
 	}
 	s.provider = p
 	return nil
@@ -0,0 +1,37 @@
+package namespace
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	poolSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cermanager",
+		Subsystem: "namespace",
+		Name:      "pool_size",
+		Help:      "Configured pool capacity per namespace type.",
+	}, []string{"type"})
+
+	inUse = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "cermanager",
+		Subsystem: "namespace",
+		Name:      "in_use",
+		Help:      "Namespaces currently checked out per type.",
+	}, []string{"type"})
+
+	getTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "cermanager",
+		Subsystem: "namespace",
+		Name:      "get_total",
+		Help:      "Total Get calls per namespace type and result.",
+	}, []string{"type", "result"})
+
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "cermanager",
+		Subsystem: "namespace",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of Get/Put requests per namespace type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "type"})
+)
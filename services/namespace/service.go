@@ -1,48 +1,50 @@
 package namespace
 
 import (
+	"bytes"
 	"context"
+	"encoding/gob"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net"
 	"os"
 	"path"
+	"sync"
+	"time"
 
-	"github.com/YLonely/cer-manager/log"
 	ns "github.com/YLonely/cer-manager/namespace"
+	"github.com/YLonely/cer-manager/pkg/api/proto"
+	"github.com/YLonely/cer-manager/pkg/reqid"
 	"github.com/YLonely/cer-manager/rootfs/containerd"
 	"github.com/YLonely/cer-manager/services"
-	"github.com/YLonely/cer-manager/utils"
+	"github.com/YLonely/cer-manager/state"
 	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-const (
-	MethodGetNamespace string = "Get"
-	MethodPutNamespace string = "Put"
-)
-
-type GetNamespaceRequest struct {
-	T   ns.NamespaceType
-	Arg interface{}
-}
-
-type PutNamespaceRequest struct {
-	T  ns.NamespaceType
-	ID int
+// namespaceRecord is the state.Manager payload for a checked-out namespace.
+type namespaceRecord struct {
+	Type int `json:"type"`
+	ID   int `json:"id"`
+	Fd   int `json:"fd"`
+	Pid  int `json:"pid"`
 }
 
-type PutNamespaceResponse struct {
-	Error string
+// mountRecord is the state.Manager payload for a checked-out mount
+// namespace. It's kept separate from namespaceRecord because a MNT checkout
+// also pins a bind-mounted rootfs that must be torn down on release; Info
+// is the same gob-encoded payload handed back to the client. Init does not
+// read Info back: it only clears leftover mount records.
+type mountRecord struct {
+	ID   int    `json:"id"`
+	Info []byte `json:"info,omitempty"`
 }
 
-type GetNamespaceResponse struct {
-	NSId int
-	Pid  int
-	Fd   int
-	Info interface{}
-}
-
-func New(root string) (services.Service, error) {
+func New(root string, baseLogger *zap.Logger, stateMgr *state.Manager) (services.Service, error) {
 	const configName = "namespace_service.json"
 	configPath := path.Join(root, configName)
 	config := defaultConfig()
@@ -61,10 +63,24 @@ func New(root string) (services.Service, error) {
 	} else if !os.IsNotExist(err) {
 		return nil, err
 	}
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(config.LogLevel)); err != nil {
+		return nil, errors.Wrap(err, "invalid log_level")
+	}
+	logger := baseLogger.WithOptions(zap.IncreaseLevel(lvl))
+	if config.LogSampling != nil {
+		logger = logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return zapcore.NewSamplerWithOptions(core, time.Second, config.LogSampling.Initial, config.LogSampling.Thereafter)
+		}))
+	}
+	logger = logger.With(zap.String("service", "namespace"))
 	return &namespaceService{
 		config:   config,
 		managers: map[ns.NamespaceType]ns.Manager{},
+		counts:   map[ns.NamespaceType]int{},
 		root:     root,
+		logger:   logger,
+		stateMgr: stateMgr,
 	}, nil
 }
 
@@ -72,9 +88,18 @@ type namespaceService struct {
 	config   serviceConfig
 	managers map[ns.NamespaceType]ns.Manager
 	root     string
+	logger   *zap.Logger
+	stateMgr *state.Manager
+	// counts tracks the in-use count of every namespace type for List,
+	// separately from the inUse gauge which isn't readable back out.
+	cm     sync.Mutex
+	counts map[ns.NamespaceType]int
 }
 
-var _ services.Service = &namespaceService{}
+var (
+	_ services.Service             = &namespaceService{}
+	_ proto.NamespaceServiceServer = &namespaceService{}
+)
 
 func (svr *namespaceService) Init() error {
 	var err error
@@ -96,31 +121,69 @@ func (svr *namespaceService) Init() error {
 	); err != nil {
 		return err
 	}
-	log.Logger(services.NamespaceService, "Init").Info("Service initialized")
+	for t, capacity := range svr.config.Capacity {
+		poolSize.WithLabelValues(fmt.Sprintf("%v", t)).Set(float64(capacity))
+	}
+	// Anything still recorded here was checked out by a previous process and
+	// never released, whether that process was killed or stopped gracefully
+	// while a client still held it. svr.managers were just created, so a
+	// whole-pool CleanUp would tear down the pool we just pre-warmed rather
+	// than reconcile anything; instead reconcile each checkout individually.
+	if orphans := svr.stateMgr.Entries(state.KindNamespace); len(orphans) > 0 {
+		svr.logger.Warn("found namespace state left by a previous process, reconciling", zap.Int("count", len(orphans)))
+		for id, raw := range orphans {
+			var rec namespaceRecord
+			if err := json.Unmarshal(raw, &rec); err != nil {
+				svr.logger.Warn("failed to decode orphan namespace state entry", zap.String("id", id), zap.Error(err))
+			} else if mgr, exists := svr.managers[ns.NamespaceType(rec.Type)]; exists {
+				// rec.Pid is only recorded for diagnostics. It can't be used
+				// to tell "this is still our fd" apart from "a new process
+				// reused the same pid" - a daemon that always restarts as
+				// container pid 1 hits that deterministically - so every
+				// orphan is routed through the pool instead of ever being
+				// closed by raw fd number.
+				if err := mgr.Put(rec.ID); err != nil {
+					svr.logger.Warn("failed to return orphan namespace to its pool", zap.String("id", id), zap.Int("pid", rec.Pid), zap.Error(err))
+				}
+			}
+			if err := svr.stateMgr.Remove(state.KindNamespace, id); err != nil {
+				svr.logger.Warn("failed to clear orphan namespace state entry", zap.String("id", id), zap.Error(err))
+			}
+		}
+	}
+	// The rootfs bind mount behind a reconciled MNT entry is torn down as
+	// a side effect of mgr.Put above; all that's left here is the record.
+	if orphans := svr.stateMgr.Entries(state.KindMount); len(orphans) > 0 {
+		svr.logger.Warn("found mount state left by a previous process, clearing", zap.Int("count", len(orphans)))
+		for id := range orphans {
+			if err := svr.stateMgr.Remove(state.KindMount, id); err != nil {
+				svr.logger.Warn("failed to clear orphan mount state entry", zap.String("id", id), zap.Error(err))
+			}
+		}
+	}
+	svr.logger.Info("service initialized")
 	return nil
 }
 
+// Handle is kept to satisfy services.Service but is no longer invoked:
+// the NamespaceService RPCs are now served directly by the grpc.Server
+// registered in cermanager.NewServer.
 func (svr *namespaceService) Handle(ctx context.Context, conn net.Conn) {
-	var methodType string
-	err := utils.ReceiveData(conn, &methodType)
-	if err != nil {
-		log.Logger(services.NamespaceService, "").WithError(err).Error()
-		conn.Close()
-		return
-	}
-	err = svr.handleRequest(methodType, conn)
-	if err != nil {
-		log.Logger(services.NamespaceService, "").WithError(err).Error()
-		conn.Close()
-		return
-	}
+	conn.Close()
 }
 
+// Stop tears down each manager's own idle, pre-warmed namespaces via
+// CleanUp. It does not touch KindNamespace/KindMount state: every recorded
+// entry describes a namespace currently checked out to a client, not one
+// CleanUp owns, and deleting its record here without releasing the
+// resource it describes would leak the namespace (or, for MNT, its bind
+// mount) with no way for Init to find it again on the next start. Entries
+// are removed by Put/Drop once the resource is released, and by Init when
+// it reconciles entries left by a previous process.
 func (svr *namespaceService) Stop() error {
 	for t, mgr := range svr.managers {
-		err := mgr.CleanUp()
-		if err != nil {
-			log.Logger(services.NamespaceService, "").WithField("namespace", t).Error(err)
+		if err := mgr.CleanUp(); err != nil {
+			svr.logger.Error("failed to clean up namespace", zap.Any("namespace", t), zap.Error(err))
 		}
 	}
 	return nil
@@ -129,72 +192,144 @@ func (svr *namespaceService) Stop() error {
 type serviceConfig struct {
 	Capacity  map[ns.NamespaceType]int      `json:"capacity"`
 	ExtraArgs map[ns.NamespaceType][]string `json:"extra_args"`
+	// LogLevel is a zapcore.Level string (e.g. "debug", "info", "warn") applied on top of the daemon's base logger.
+	LogLevel string `json:"log_level"`
+	// LogSampling configures zap's log sampler on top of the daemon's base
+	// logger. A nil value leaves sampling disabled.
+	LogSampling *LogSampling `json:"log_sampling,omitempty"`
 }
 
-func (svr *namespaceService) handleGetNamespace(conn net.Conn, r GetNamespaceRequest) error {
-	log.WithInterface(log.Logger(services.NamespaceService, "GetNamespace"), "request", r).Info()
-	rsp := GetNamespaceResponse{}
-	if mgr, exists := svr.managers[r.T]; !exists {
-		rsp.Fd = -1
-		rsp.Info = "No such namespace"
-	} else {
-		id, fd, info, err := mgr.Get(r.Arg)
-		if err != nil {
-			rsp.Fd = -1
-			rsp.Info = err.Error()
-		} else {
-			rsp.Fd = fd
-			rsp.NSId = id
-			rsp.Info = info
-			rsp.Pid = os.Getpid()
+// LogSampling mirrors the knobs of zapcore.NewSamplerWithOptions: within
+// each one-second tick, the first Initial entries of a given level+message
+// are logged, and only every Thereafter-th one after that.
+type LogSampling struct {
+	Initial    int `json:"initial"`
+	Thereafter int `json:"thereafter"`
+}
+
+// Get implements proto.NamespaceServiceServer.
+func (svr *namespaceService) Get(ctx context.Context, r *proto.GetNamespaceRequest) (*proto.GetNamespaceResponse, error) {
+	l := svr.logger.With(zap.String("request_id", reqid.FromContext(ctx)))
+	l.Info("GetNamespace", zap.Any("request", r))
+	t := ns.NamespaceType(r.Type)
+	mgr, exists := svr.managers[t]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "no such namespace type %v", t)
+	}
+	var arg interface{}
+	if len(r.Arg) > 0 {
+		if err := gob.NewDecoder(bytes.NewReader(r.Arg)).Decode(&arg); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to decode arg: %s", err.Error())
 		}
 	}
-	if err := utils.SendWithSizePrefix(conn, rsp); err != nil {
-		return err
+	typeLabel := fmt.Sprintf("%v", t)
+	start := time.Now()
+	id, fd, info, err := mgr.Get(arg)
+	requestDuration.WithLabelValues("Get", typeLabel).Observe(time.Since(start).Seconds())
+	if err != nil {
+		getTotal.WithLabelValues(typeLabel, "error").Inc()
+		return nil, status.Error(codes.Internal, err.Error())
 	}
-	log.WithInterface(log.Logger(services.NamespaceService, "GetNamespace"), "response", rsp).Info()
-	return nil
-}
-
-func (svr *namespaceService) handlePutNamespace(conn net.Conn, r PutNamespaceRequest) error {
-	log.WithInterface(log.Logger(services.NamespaceService, "PutNamespace"), "request", r).Info()
-	rsp := PutNamespaceResponse{}
-	if mgr, exists := svr.managers[r.T]; !exists {
-		rsp.Error = "No such namespace"
-	} else {
-		err := mgr.Put(r.ID)
-		if err != nil {
-			rsp.Error = err.Error()
+	getTotal.WithLabelValues(typeLabel, "success").Inc()
+	// Encode info before committing the checkout: once inUse/counts are
+	// bumped and the state is persisted below, the namespace is considered
+	// handed out, and there'd be no client-visible id left to release it if
+	// we failed after that point.
+	var infoBuf bytes.Buffer
+	if info != nil {
+		if err := gob.NewEncoder(&infoBuf).Encode(&info); err != nil {
+			if putErr := mgr.Put(id); putErr != nil {
+				l.Warn("failed to return namespace to its pool after encode failure", zap.Error(putErr))
+			}
+			return nil, status.Errorf(codes.Internal, "failed to encode info: %s", err.Error())
 		}
 	}
-	if err := utils.SendWithSizePrefix(conn, rsp); err != nil {
-		return err
+	inUse.WithLabelValues(typeLabel).Inc()
+	svr.cm.Lock()
+	svr.counts[t]++
+	svr.cm.Unlock()
+	stateKey := fmt.Sprintf("%s-%d", typeLabel, id)
+	if err := svr.stateMgr.Put(state.KindNamespace, stateKey, namespaceRecord{Type: int(t), ID: id, Fd: fd, Pid: os.Getpid()}); err != nil {
+		l.Warn("failed to persist namespace state", zap.Error(err))
 	}
-	log.WithInterface(log.Logger(services.NamespaceService, "PutNamespace"), "response", rsp).Info()
-	return nil
+	if t == ns.MNT {
+		if err := svr.stateMgr.Put(state.KindMount, stateKey, mountRecord{ID: id, Info: infoBuf.Bytes()}); err != nil {
+			l.Warn("failed to persist mount state", zap.Error(err))
+		}
+	}
+	rsp := &proto.GetNamespaceResponse{
+		NsId: int32(id),
+		Pid:  int32(os.Getpid()),
+		Fd:   int32(fd),
+		Info: infoBuf.Bytes(),
+	}
+	l.Info("GetNamespace", zap.Any("response", rsp))
+	return rsp, nil
 }
 
-func (svr *namespaceService) handleRequest(method string, conn net.Conn) error {
-	switch method {
-	case MethodGetNamespace:
-		{
-			var r GetNamespaceRequest
-			if err := utils.ReceiveData(conn, &r); err != nil {
-				return err
-			}
-			return svr.handleGetNamespace(conn, r)
+// Put implements proto.NamespaceServiceServer.
+func (svr *namespaceService) Put(ctx context.Context, r *proto.PutNamespaceRequest) (*proto.PutNamespaceResponse, error) {
+	l := svr.logger.With(zap.String("request_id", reqid.FromContext(ctx)))
+	l.Info("PutNamespace", zap.Any("request", r))
+	t := ns.NamespaceType(r.Type)
+	mgr, exists := svr.managers[t]
+	if !exists {
+		return nil, status.Errorf(codes.NotFound, "no such namespace type %v", t)
+	}
+	typeLabel := fmt.Sprintf("%v", t)
+	start := time.Now()
+	rsp := &proto.PutNamespaceResponse{}
+	err := mgr.Put(int(r.Id))
+	requestDuration.WithLabelValues("Put", typeLabel).Observe(time.Since(start).Seconds())
+	if err != nil {
+		rsp.Error = err.Error()
+	} else {
+		inUse.WithLabelValues(typeLabel).Dec()
+		svr.cm.Lock()
+		svr.counts[t]--
+		svr.cm.Unlock()
+		stateKey := fmt.Sprintf("%s-%d", typeLabel, r.Id)
+		if err := svr.stateMgr.Remove(state.KindNamespace, stateKey); err != nil {
+			l.Warn("failed to clear namespace state", zap.Error(err))
 		}
-	case MethodPutNamespace:
-		{
-			var r PutNamespaceRequest
-			if err := utils.ReceiveData(conn, &r); err != nil {
-				return err
+		if t == ns.MNT {
+			if err := svr.stateMgr.Remove(state.KindMount, stateKey); err != nil {
+				l.Warn("failed to clear mount state", zap.Error(err))
 			}
-			return svr.handlePutNamespace(conn, r)
 		}
-	default:
-		return errors.New("Unknown method type")
 	}
+	l.Info("PutNamespace", zap.Any("response", rsp))
+	return rsp, nil
+}
+
+// List implements proto.NamespaceServiceServer, dumping the current pool
+// state of every namespace type for operators inspecting the daemon.
+func (svr *namespaceService) List(ctx context.Context, r *proto.ListNamespacesRequest) (*proto.ListNamespacesResponse, error) {
+	rsp := &proto.ListNamespacesResponse{}
+	svr.cm.Lock()
+	defer svr.cm.Unlock()
+	for _, t := range []ns.NamespaceType{ns.IPC, ns.MNT, ns.UTS} {
+		if _, exists := svr.managers[t]; !exists {
+			continue
+		}
+		rsp.Pools = append(rsp.Pools, &proto.NamespacePoolInfo{
+			Type:     int32(t),
+			Capacity: int32(svr.config.Capacity[t]),
+			InUse:    int32(svr.counts[t]),
+		})
+	}
+	return rsp, nil
+}
+
+// Drop implements proto.NamespaceServiceServer. It forces a namespace back
+// into its pool, exactly like Put, so operators can reclaim one without
+// waiting for its owner.
+func (svr *namespaceService) Drop(ctx context.Context, r *proto.DropNamespaceRequest) (*proto.DropNamespaceResponse, error) {
+	putRsp, err := svr.Put(ctx, &proto.PutNamespaceRequest{Type: r.Type, Id: r.Id})
+	if err != nil {
+		return nil, err
+	}
+	return &proto.DropNamespaceResponse{Error: putRsp.Error}, nil
 }
 
 func mergeConfig(to, from *serviceConfig) error {
@@ -209,6 +344,12 @@ func mergeConfig(to, from *serviceConfig) error {
 			to.ExtraArgs[t] = v
 		}
 	}
+	if from.LogLevel != "" {
+		to.LogLevel = from.LogLevel
+	}
+	if from.LogSampling != nil {
+		to.LogSampling = from.LogSampling
+	}
 	return nil
 }
 
@@ -220,5 +361,6 @@ func defaultConfig() serviceConfig {
 			ns.MNT: 5,
 		},
 		ExtraArgs: map[ns.NamespaceType][]string{},
+		LogLevel:  "info",
 	}
-}
\ No newline at end of file
+}
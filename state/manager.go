@@ -0,0 +1,145 @@
+// Package state gives the daemon's services a crash-safe record of the
+// host-level resources they hand out (namespace fds/pids, bind mounts,
+// prepared checkpoint targets) so a SIGKILL between operations doesn't
+// leak them. This mirrors the "state manager" pattern used by daemons
+// like netbird/nomad to recover cleanly after an unclean shutdown.
+package state
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Kind groups entries so a caller can replay and reconcile each category
+// (namespaces, mounts, checkpoint targets) independently on startup.
+type Kind string
+
+const (
+	KindNamespace  Kind = "namespace"
+	KindMount      Kind = "mount"
+	KindCheckpoint Kind = "checkpoint"
+)
+
+// entry is one on-disk record: an opaque, kind-specific payload keyed by id.
+type entry struct {
+	Kind Kind            `json:"kind"`
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Manager persists entries to a json file under root/state. Every mutation
+// is written to a temp file and renamed into place, so a process killed
+// mid-write always leaves either the previous or the new state, never a
+// torn one.
+type Manager struct {
+	path    string
+	m       sync.Mutex
+	entries map[Kind]map[string]json.RawMessage
+}
+
+// NewManager loads the state file under root/state, creating the directory
+// if needed. A missing state file is treated as empty; the file itself is
+// first written by the next Put or Remove.
+func NewManager(root string) (*Manager, error) {
+	dir := path.Join(root, "state")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	mgr := &Manager{
+		path:    path.Join(dir, "state.json"),
+		entries: map[Kind]map[string]json.RawMessage{},
+	}
+	if err := mgr.load(); err != nil {
+		return nil, errors.Wrap(err, "failed to load state file")
+	}
+	return mgr, nil
+}
+
+func (mgr *Manager) load() error {
+	content, err := ioutil.ReadFile(mgr.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(content) == 0 {
+		return nil
+	}
+	var entries []entry
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if mgr.entries[e.Kind] == nil {
+			mgr.entries[e.Kind] = map[string]json.RawMessage{}
+		}
+		mgr.entries[e.Kind][e.ID] = e.Data
+	}
+	return nil
+}
+
+// Put records data under kind/id and atomically persists it.
+func (mgr *Manager) Put(kind Kind, id string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	mgr.m.Lock()
+	defer mgr.m.Unlock()
+	if mgr.entries[kind] == nil {
+		mgr.entries[kind] = map[string]json.RawMessage{}
+	}
+	mgr.entries[kind][id] = raw
+	return mgr.flush()
+}
+
+// Remove deletes the kind/id record and atomically persists the change.
+func (mgr *Manager) Remove(kind Kind, id string) error {
+	mgr.m.Lock()
+	defer mgr.m.Unlock()
+	if mgr.entries[kind] == nil {
+		return nil
+	}
+	if _, exists := mgr.entries[kind][id]; !exists {
+		return nil
+	}
+	delete(mgr.entries[kind], id)
+	return mgr.flush()
+}
+
+// Entries returns a snapshot of every id/data pair recorded under kind, for
+// a caller to replay and reconcile against live host state on startup.
+func (mgr *Manager) Entries(kind Kind) map[string]json.RawMessage {
+	mgr.m.Lock()
+	defer mgr.m.Unlock()
+	out := make(map[string]json.RawMessage, len(mgr.entries[kind]))
+	for id, data := range mgr.entries[kind] {
+		out[id] = data
+	}
+	return out
+}
+
+// flush must be called with mgr.m held.
+func (mgr *Manager) flush() error {
+	var entries []entry
+	for kind, byID := range mgr.entries {
+		for id, data := range byID {
+			entries = append(entries, entry{Kind: kind, ID: id, Data: data})
+		}
+	}
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	tmp := mgr.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, content, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, mgr.path)
+}
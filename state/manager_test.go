@@ -0,0 +1,69 @@
+package state
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type testRecord struct {
+	Value string `json:"value"`
+}
+
+func TestManagerPutEntriesRemove(t *testing.T) {
+	mgr, err := NewManager(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if err := mgr.Put(KindNamespace, "a", testRecord{Value: "one"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	entries := mgr.Entries(KindNamespace)
+	raw, ok := entries["a"]
+	if !ok {
+		t.Fatalf("expected entry %q, got %v", "a", entries)
+	}
+	var rec testRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if rec.Value != "one" {
+		t.Fatalf("expected value %q, got %q", "one", rec.Value)
+	}
+	if err := mgr.Remove(KindNamespace, "a"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if entries := mgr.Entries(KindNamespace); len(entries) != 0 {
+		t.Fatalf("expected no entries after Remove, got %v", entries)
+	}
+}
+
+// TestManagerSurvivesReload verifies the write-then-rename persistence: a
+// fresh Manager loaded against the same root sees every entry a prior
+// Manager wrote, as if reconciling after a SIGKILL between operations.
+func TestManagerSurvivesReload(t *testing.T) {
+	root := t.TempDir()
+	mgr, err := NewManager(root)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	if err := mgr.Put(KindMount, "m1", testRecord{Value: "mounted"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := mgr.Put(KindCheckpoint, "c1", testRecord{Value: "prepared"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	reloaded, err := NewManager(root)
+	if err != nil {
+		t.Fatalf("NewManager (reload): %v", err)
+	}
+	if entries := reloaded.Entries(KindMount); len(entries) != 1 {
+		t.Fatalf("expected 1 mount entry after reload, got %v", entries)
+	}
+	if entries := reloaded.Entries(KindCheckpoint); len(entries) != 1 {
+		t.Fatalf("expected 1 checkpoint entry after reload, got %v", entries)
+	}
+	if entries := reloaded.Entries(KindNamespace); len(entries) != 0 {
+		t.Fatalf("expected no namespace entries, got %v", entries)
+	}
+}